@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/soft-serve/server/backend"
+	"github.com/charmbracelet/soft-serve/server/backend/sqlite"
+	"github.com/charmbracelet/soft-serve/server/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	exportOutput string
+	exportJson   bool // nolint: revive
+
+	exportConfig = &cobra.Command{
+		Use:   "export-config",
+		Short: "Export config to legacy format",
+		Long: `Export-config walks the sqlite backend and reconstructs the legacy
+Config/User/RepoConfig structs, writing YAML (or JSON with --json) to stdout
+or to the file given by --output. This is the reverse of migrate-config and
+lets operators diff config across installs or check the migrated state into
+git.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			cfg := config.DefaultConfig()
+			sb, err := sqlite.NewSqliteBackend(ctx, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create sqlite backend: %w", err)
+			}
+
+			ocfg, err := exportLegacyConfig(sb)
+			if err != nil {
+				return fmt.Errorf("failed to export config: %w", err)
+			}
+
+			var out []byte
+			if exportJson {
+				out, err = json.MarshalIndent(ocfg, "", "  ")
+			} else {
+				out, err = yaml.Marshal(ocfg)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+
+			if exportOutput == "" {
+				_, err = os.Stdout.Write(out)
+				return err
+			}
+
+			return os.WriteFile(exportOutput, out, 0600)
+		},
+	}
+)
+
+func init() {
+	exportConfig.Flags().StringVarP(&exportOutput, "output", "o", "", "path to write the exported config to (defaults to stdout)")
+	exportConfig.Flags().BoolVar(&exportJson, "json", false, "export as JSON instead of YAML")
+}
+
+// exportLegacyConfig reconstructs the legacy Config struct from the sqlite
+// backend: repos via sb.Repositories(), metadata and collaborators via the
+// same getters migrate-config's setters mirror, and user public keys via
+// backend.MarshalAuthorizedKey.
+func exportLegacyConfig(sb *sqlite.SqliteBackend) (*Config, error) {
+	ocfg := &Config{
+		Name:         sb.Name(),
+		AnonAccess:   sb.AnonAccess().String(),
+		AllowKeyless: sb.AllowKeyless(),
+	}
+
+	repos, err := sb.Repositories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	collabsByRepo := make(map[string][]string, len(repos))
+	for _, repo := range repos {
+		collabs, err := sb.Collaborators(repo.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collaborators for %s: %w", repo.Name(), err)
+		}
+		collabsByRepo[repo.Name()] = collabs
+
+		ocfg.Repos = append(ocfg.Repos, RepoConfig{
+			Name:    repo.ProjectName(),
+			Repo:    repo.Name(),
+			Note:    repo.Description(),
+			Private: repo.IsPrivate(),
+			Collabs: collabs,
+		})
+	}
+
+	users, err := sb.Users()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	for _, user := range users {
+		pubkeys := user.PublicKeys()
+		keys := make([]string, 0, len(pubkeys))
+		for _, pk := range pubkeys {
+			keys = append(keys, backend.MarshalAuthorizedKey(pk))
+		}
+
+		var collabRepos []string
+		for name, collabs := range collabsByRepo {
+			for _, collab := range collabs {
+				if collab == user.Username() {
+					collabRepos = append(collabRepos, name)
+				}
+			}
+		}
+		sort.Strings(collabRepos)
+
+		ocfg.Users = append(ocfg.Users, User{
+			Name:           user.Username(),
+			Admin:          user.IsAdmin(),
+			PublicKeys:     keys,
+			CollabRepos:    collabRepos,
+			PGPKeys:        user.PGPKeys(),
+			SSHSigningKeys: user.SSHSigningKeys(),
+		})
+	}
+
+	// sb.Repositories()/sb.Users() make no ordering guarantee, so sort the
+	// repo and user lists here rather than relying on sqlite's unspecified
+	// row order; each user's collab repos were sorted above, since
+	// collabsByRepo is a Go map. This keeps export-config's output stable
+	// for diffing across installs or checking into git. The collaborator
+	// and key lists inside each entry keep the order the database returns.
+	sort.Slice(ocfg.Repos, func(i, j int) bool { return ocfg.Repos[i].Repo < ocfg.Repos[j].Repo })
+	sort.Slice(ocfg.Users, func(i, j int) bool { return ocfg.Users[i].Name < ocfg.Users[j].Name })
+
+	return ocfg, nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// forgeRepo is a repository as reported by an external forge, normalized
+// across GitHub, Gitea, and GitLab's APIs.
+type forgeRepo struct {
+	Name          string
+	FullName      string
+	Description   string
+	Private       bool
+	CloneURL      string
+	Collaborators []string
+}
+
+// forgeClient enumerates repositories from an external forge.
+type forgeClient interface {
+	ListRepositories(ctx context.Context, since time.Time) ([]forgeRepo, error)
+}
+
+// newForgeClient returns a forgeClient for the given source.
+func newForgeClient(src forgeSource, token string) (forgeClient, error) {
+	switch src.kind {
+	case "github":
+		return &githubClient{owner: src.path, token: token}, nil
+	case "gitea":
+		return &giteaClient{host: src.host, org: src.path, token: token}, nil
+	case "gitlab":
+		return &gitlabClient{host: src.host, group: src.path, token: token}, nil
+	default:
+		return nil, fmt.Errorf("unsupported forge %q", src.kind)
+	}
+}
+
+type githubClient struct {
+	owner string
+	token string
+}
+
+func (c *githubClient) ListRepositories(ctx context.Context, since time.Time) ([]forgeRepo, error) {
+	url := fmt.Sprintf("https://api.github.com/users/%s/repos?per_page=100", c.owner)
+	var raw []struct {
+		Name        string `json:"name"`
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Private     bool   `json:"private"`
+		CloneURL    string `json:"clone_url"`
+		UpdatedAt   string `json:"updated_at"`
+	}
+	if err := forgeGetJSON(ctx, url, c.token, &raw); err != nil {
+		return nil, err
+	}
+
+	var repos []forgeRepo
+	for _, r := range raw {
+		if !since.IsZero() {
+			if updated, err := time.Parse(time.RFC3339, r.UpdatedAt); err == nil && updated.Before(since) {
+				continue
+			}
+		}
+		repos = append(repos, forgeRepo{
+			Name:        r.Name,
+			FullName:    r.FullName,
+			Description: r.Description,
+			Private:     r.Private,
+			CloneURL:    r.CloneURL,
+		})
+	}
+	return repos, nil
+}
+
+type giteaClient struct {
+	host  string
+	org   string
+	token string
+}
+
+func (c *giteaClient) ListRepositories(ctx context.Context, since time.Time) ([]forgeRepo, error) {
+	url := fmt.Sprintf("https://%s/api/v1/orgs/%s/repos", c.host, c.org)
+	var raw []struct {
+		Name        string `json:"name"`
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Private     bool   `json:"private"`
+		CloneURL    string `json:"clone_url"`
+		UpdatedAt   string `json:"updated_at"`
+	}
+	if err := forgeGetJSON(ctx, url, c.token, &raw); err != nil {
+		return nil, err
+	}
+
+	var repos []forgeRepo
+	for _, r := range raw {
+		if !since.IsZero() {
+			if updated, err := time.Parse(time.RFC3339, r.UpdatedAt); err == nil && updated.Before(since) {
+				continue
+			}
+		}
+		repos = append(repos, forgeRepo{
+			Name:        r.Name,
+			FullName:    r.FullName,
+			Description: r.Description,
+			Private:     r.Private,
+			CloneURL:    r.CloneURL,
+		})
+	}
+	return repos, nil
+}
+
+type gitlabClient struct {
+	host  string
+	group string
+	token string
+}
+
+func (c *gitlabClient) ListRepositories(ctx context.Context, since time.Time) ([]forgeRepo, error) {
+	url := fmt.Sprintf("https://%s/api/v4/groups/%s/projects", c.host, c.group)
+	var raw []struct {
+		Name              string `json:"name"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		Description       string `json:"description"`
+		Visibility        string `json:"visibility"`
+		HTTPURLToRepo     string `json:"http_url_to_repo"`
+		LastActivityAt    string `json:"last_activity_at"`
+	}
+	if err := forgeGetJSON(ctx, url, c.token, &raw); err != nil {
+		return nil, err
+	}
+
+	var repos []forgeRepo
+	for _, r := range raw {
+		if !since.IsZero() {
+			if updated, err := time.Parse(time.RFC3339, r.LastActivityAt); err == nil && updated.Before(since) {
+				continue
+			}
+		}
+		repos = append(repos, forgeRepo{
+			Name:        r.Name,
+			FullName:    r.PathWithNamespace,
+			Description: r.Description,
+			Private:     r.Visibility != "public",
+			CloneURL:    r.HTTPURLToRepo,
+		})
+	}
+	return repos, nil
+}
+
+// forgeGetJSON performs an authenticated GET request and decodes the JSON
+// response body into v.
+func forgeGetJSON(ctx context.Context, url, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("forge API request failed: %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/server/backend"
+	"github.com/charmbracelet/soft-serve/server/backend/sqlite"
+	"github.com/charmbracelet/soft-serve/server/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFrom    string
+	importToken   string
+	importInclude string
+	importExclude string
+	importSince   string
+
+	importCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import repositories from an external forge",
+		Long: `Import enumerates repositories from an external forge (GitHub, Gitea, or
+GitLab) and mirrors each one into the soft-serve sqlite backend, preserving
+description and visibility. Collaborators are not fetched from the forge.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if importFrom == "" {
+				return fmt.Errorf("missing --from, e.g. --from github://my-user")
+			}
+
+			forge, err := parseForgeSource(importFrom)
+			if err != nil {
+				return err
+			}
+
+			var since time.Time
+			if importSince != "" {
+				since, err = time.Parse(time.RFC3339, importSince)
+				if err != nil {
+					return fmt.Errorf("failed to parse --since: %w", err)
+				}
+			}
+
+			ctx := cmd.Context()
+			cfg := config.DefaultConfig()
+			sb, err := sqlite.NewSqliteBackend(ctx, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create sqlite backend: %w", err)
+			}
+
+			client, err := newForgeClient(forge, importToken)
+			if err != nil {
+				return fmt.Errorf("failed to create forge client: %w", err)
+			}
+
+			repos, err := client.ListRepositories(ctx, since)
+			if err != nil {
+				return fmt.Errorf("failed to list repositories: %w", err)
+			}
+
+			for _, repo := range repos {
+				if !matchesGlob(repo.Name, importInclude, importExclude) {
+					continue
+				}
+
+				log.Infof("Importing %s", repo.FullName)
+				dst := filepath.Join(cfg.DataPath, "repos", repo.Name) + ".git"
+				if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+					return fmt.Errorf("failed to create repos directory: %w", err)
+				}
+
+				cloneURL := repo.CloneURL
+				if importToken != "" {
+					cloneURL, err = authenticatedCloneURL(repo.CloneURL)
+					if err != nil {
+						log.Errorf("failed to build clone URL for %s: %s", repo.FullName, err)
+						continue
+					}
+				}
+
+				if err := mirrorClone(cloneURL, dst, importToken); err != nil {
+					log.Errorf("failed to mirror %s: %s", repo.FullName, err)
+					continue
+				}
+
+				if _, err := sb.CreateRepository(repo.Name, backend.RepositoryOptions{
+					Private: repo.Private,
+				}); err != nil {
+					log.Errorf("failed to create repository %s: %s", repo.Name, err)
+					continue
+				}
+
+				if err := sb.SetDescription(repo.Name, repo.Description); err != nil {
+					log.Errorf("failed to set description for %s: %s", repo.Name, err)
+				}
+
+				if err := sb.SetPrivate(repo.Name, repo.Private); err != nil {
+					log.Errorf("failed to set private for %s: %s", repo.Name, err)
+				}
+
+				for _, collab := range repo.Collaborators {
+					if err := sb.AddCollaborator(repo.Name, collab); err != nil {
+						log.Errorf("failed to add collaborator %s to %s: %s", collab, repo.Name, err)
+					}
+				}
+
+				if err := sb.RecordMirror(repo.Name, repo.CloneURL); err != nil {
+					log.Errorf("failed to record mirror origin for %s: %s", repo.Name, err)
+				}
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "", "forge to import from, e.g. github://user, gitea://host/org, gitlab://host/group")
+	importCmd.Flags().StringVar(&importToken, "token", "", "access token used to authenticate against the forge API")
+	importCmd.Flags().StringVar(&importInclude, "include", "", "only import repositories whose name matches this glob")
+	importCmd.Flags().StringVar(&importExclude, "exclude", "", "skip repositories whose name matches this glob")
+	importCmd.Flags().StringVar(&importSince, "since", "", "only import repositories updated after this RFC3339 timestamp (for incremental re-imports)")
+}
+
+// forgeSource identifies the kind of forge and the user/org/group to import
+// from, as parsed out of a --from URL such as "github://user".
+type forgeSource struct {
+	kind string // "github", "gitea", or "gitlab"
+	host string
+	path string
+}
+
+func (f forgeSource) String() string {
+	if f.host == "" {
+		return f.kind + "://" + f.path
+	}
+	return f.kind + "://" + f.host + "/" + f.path
+}
+
+func parseForgeSource(raw string) (forgeSource, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return forgeSource{}, fmt.Errorf("invalid --from %q, expected scheme://path", raw)
+	}
+
+	switch scheme {
+	case "github":
+		return forgeSource{kind: scheme, path: rest}, nil
+	case "gitea", "gitlab":
+		host, path, ok := strings.Cut(rest, "/")
+		if !ok {
+			return forgeSource{}, fmt.Errorf("invalid --from %q, expected %s://host/org", raw, scheme)
+		}
+		return forgeSource{kind: scheme, host: host, path: path}, nil
+	default:
+		return forgeSource{}, fmt.Errorf("unsupported forge %q", scheme)
+	}
+}
+
+// matchesGlob reports whether name should be imported given the optional
+// include and exclude glob patterns.
+func matchesGlob(name, include, exclude string) bool {
+	if include != "" {
+		if ok, _ := filepath.Match(include, name); !ok {
+			return false
+		}
+	}
+	if exclude != "" {
+		if ok, _ := filepath.Match(exclude, name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mirrorClone runs `git clone --mirror` from src into dst. If token is
+// non-empty, it's supplied to git via a short-lived GIT_ASKPASS helper
+// rather than embedded in src, so it never appears in argv -- a secret in
+// the clone URL would otherwise be visible to any local user for the
+// duration of the clone, via /proc/<pid>/cmdline or `ps aux`.
+func mirrorClone(src, dst, token string) error {
+	cmd := exec.Command("git", "clone", "--mirror", src, dst)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if token != "" {
+		askpass, cleanup, err := newAskpassHelper(token)
+		if err != nil {
+			return fmt.Errorf("failed to set up credential helper: %w", err)
+		}
+		defer cleanup()
+		cmd.Env = append(os.Environ(), "GIT_ASKPASS="+askpass)
+	}
+
+	return cmd.Run()
+}
+
+// authenticatedCloneURL sets rawURL's userinfo to "x-access-token", the
+// conventional username GitHub, Gitea, and GitLab all accept for token
+// auth over HTTPS. Since that username isn't secret, it's fine in argv;
+// paired with mirrorClone's GIT_ASKPASS helper, git never needs to be
+// told the actual token on the command line. If rawURL isn't http(s), it
+// is returned unchanged.
+func authenticatedCloneURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse clone URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawURL, nil
+	}
+
+	u.User = url.User("x-access-token")
+	return u.String(), nil
+}
+
+// newAskpassHelper writes a short-lived, owner-only script that prints
+// token to stdout, for use as a GIT_ASKPASS credential helper, along with
+// a cleanup func that removes it. Since authenticatedCloneURL already put
+// the username in the clone URL, git only ever invokes this for the
+// password prompt, so it can print token unconditionally without
+// inspecting the prompt text git passes as its argument.
+func newAskpassHelper(token string) (path string, cleanup func(), err error) {
+	f, err := os.CreateTemp("", "soft-import-askpass-*")
+	if err != nil {
+		return "", nil, err
+	}
+	path = f.Name()
+	cleanup = func() { os.Remove(path) } // nolint: errcheck
+
+	script := "#!/bin/sh\ncat <<'SOFT_IMPORT_TOKEN'\n" + token + "\nSOFT_IMPORT_TOKEN\n"
+	if _, err := f.WriteString(script); err != nil {
+		f.Close() // nolint: errcheck
+		cleanup()
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := os.Chmod(path, 0700); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return path, cleanup, nil
+}
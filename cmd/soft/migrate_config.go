@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/server/backend"
 	"github.com/charmbracelet/soft-serve/server/backend/sqlite"
+	"github.com/charmbracelet/soft-serve/server/backend/storage"
 	"github.com/charmbracelet/soft-serve/server/config"
 	"github.com/charmbracelet/soft-serve/server/utils"
 	"github.com/spf13/cobra"
@@ -20,6 +23,13 @@ import (
 )
 
 var (
+	migrateStorageURL string
+	migrateSkipLFS    bool
+	migrateSkipFsck   bool
+	migrateKeepHooks  bool
+	migrateDryRun     bool
+	migrateReportFmt  string
+
 	migrateConfig = &cobra.Command{
 		Use:   "migrate-config",
 		Short: "Migrate config to new format",
@@ -29,6 +39,22 @@ var (
 			bindAddr := os.Getenv("SOFT_SERVE_BIND_ADDRESS")
 			ctx := cmd.Context()
 			cfg := config.DefaultConfig()
+			cfg.Storage.URL = migrateStorageURL
+			if cfg.Storage.URL == "" {
+				// Default to the on-disk repos directory so a migration
+				// that doesn't pass --storage keeps the pre-existing
+				// behavior instead of writing relative to the process's
+				// working directory.
+				cfg.Storage.URL = filepath.Join(cfg.DataPath, "repos")
+			}
+
+			report := &migrationReport{}
+
+			if migrateDryRun {
+				log.Info("Running in --dry-run mode, no changes will be made")
+				return runDryMigration(cfg, keyPath, reposPath, report)
+			}
+
 			sb, err := sqlite.NewSqliteBackend(ctx, cfg)
 			if err != nil {
 				return fmt.Errorf("failed to create sqlite backend: %w", err)
@@ -36,6 +62,38 @@ var (
 
 			cfg = cfg.WithBackend(sb)
 
+			store, err := storage.New(cfg.Storage.URL)
+			if err != nil {
+				return fmt.Errorf("failed to create storage backend: %w", err)
+			}
+
+			mtx, err := sb.BeginMigration(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to begin migration transaction: %w", err)
+			}
+
+			staging := filepath.Join(cfg.DataPath, fmt.Sprintf(".migrate-%d", time.Now().Unix()))
+			if err := os.MkdirAll(staging, 0700); err != nil {
+				return fmt.Errorf("failed to create staging directory: %w", err)
+			}
+
+			var finalizedRepos []string
+			rollback := func(cause error) error {
+				log.Errorf("migration failed, rolling back: %s", cause)
+				for _, name := range finalizedRepos {
+					if err := deleteFromStorage(store, utils.SanitizeRepo(name)+".git"); err != nil {
+						log.Errorf("failed to clean up finalized repo %s from storage: %s", name, err)
+					}
+				}
+				if err := os.RemoveAll(staging); err != nil {
+					log.Errorf("failed to remove staging directory: %s", err)
+				}
+				if err := mtx.Rollback(); err != nil {
+					log.Errorf("failed to roll back migration transaction: %s", err)
+				}
+				return cause
+			}
+
 			// Set SSH listen address
 			log.Info("Setting SSH listen address...")
 			if bindAddr != "" {
@@ -46,11 +104,11 @@ var (
 			log.Info("Copying SSH host key...")
 			if keyPath != "" {
 				if err := os.MkdirAll(filepath.Join(cfg.DataPath, "ssh"), 0700); err != nil {
-					return fmt.Errorf("failed to create ssh directory: %w", err)
+					return rollback(fmt.Errorf("failed to create ssh directory: %w", err))
 				}
 
 				if err := copyFile(keyPath, filepath.Join(cfg.DataPath, "ssh", filepath.Base(keyPath))); err != nil {
-					return fmt.Errorf("failed to copy ssh key: %w", err)
+					return rollback(fmt.Errorf("failed to copy ssh key: %w", err))
 				}
 
 				if err := copyFile(keyPath+".pub", filepath.Join(cfg.DataPath, "ssh", filepath.Base(keyPath))+".pub"); err != nil {
@@ -62,45 +120,9 @@ var (
 
 			// Read config
 			log.Info("Reading config repository...")
-			r, err := git.Open(filepath.Join(reposPath, "config"))
-			if err != nil {
-				return fmt.Errorf("failed to open config repo: %w", err)
-			}
-
-			head, err := r.HEAD()
-			if err != nil {
-				return fmt.Errorf("failed to get head: %w", err)
-			}
-
-			tree, err := r.TreePath(head, "")
-			if err != nil {
-				return fmt.Errorf("failed to get tree: %w", err)
-			}
-
-			isJson := false // nolint: revive
-			te, err := tree.TreeEntry("config.yaml")
-			if err != nil {
-				te, err = tree.TreeEntry("config.json")
-				if err != nil {
-					return fmt.Errorf("failed to get config file: %w", err)
-				}
-				isJson = true
-			}
-
-			cc, err := te.Contents()
+			ocfg, err := readLegacyConfig(reposPath)
 			if err != nil {
-				return fmt.Errorf("failed to get config contents: %w", err)
-			}
-
-			var ocfg Config
-			if isJson {
-				if err := json.Unmarshal(cc, &ocfg); err != nil {
-					return fmt.Errorf("failed to unmarshal config: %w", err)
-				}
-			} else {
-				if err := yaml.Unmarshal(cc, &ocfg); err != nil {
-					return fmt.Errorf("failed to unmarshal config: %w", err)
-				}
+				return rollback(err)
 			}
 
 			// Set server name
@@ -111,22 +133,23 @@ var (
 
 			// Set server settings
 			log.Info("Setting server settings...")
-			if cfg.Backend.SetAllowKeyless(ocfg.AllowKeyless) != nil {
+			if mtx.SetAllowKeyless(ocfg.AllowKeyless) != nil {
 				fmt.Fprintf(os.Stderr, "failed to set allow keyless\n")
 			}
 			anon := backend.ParseAccessLevel(ocfg.AnonAccess)
 			if anon >= 0 {
-				if err := sb.SetAnonAccess(anon); err != nil {
+				if err := mtx.SetAnonAccess(anon); err != nil {
 					fmt.Fprintf(os.Stderr, "failed to set anon access: %s\n", err)
 				}
 			}
 
 			// Copy repos
+			var reposCreated []string
 			if reposPath != "" {
-				log.Info("Copying repos...")
+				log.Info("Migrating repos...")
 				dirs, err := os.ReadDir(reposPath)
 				if err != nil {
-					return fmt.Errorf("failed to read repos directory: %w", err)
+					return rollback(fmt.Errorf("failed to read repos directory: %w", err))
 				}
 
 				for _, dir := range dirs {
@@ -138,47 +161,54 @@ var (
 						continue
 					}
 
-					log.Infof("  Copying repo %s", dir.Name())
-					if err := os.MkdirAll(filepath.Join(cfg.DataPath, "repos"), 0700); err != nil {
-						return fmt.Errorf("failed to create repos directory: %w", err)
-					}
-
+					log.Infof("  Migrating repo %s", dir.Name())
 					src := utils.SanitizeRepo(filepath.Join(reposPath, dir.Name()))
-					dst := utils.SanitizeRepo(filepath.Join(cfg.DataPath, "repos", dir.Name())) + ".git"
-					if err := copyDir(src, dst); err != nil {
-						return fmt.Errorf("failed to copy repo: %w", err)
+					repoStaging := filepath.Join(staging, "repos", utils.SanitizeRepo(dir.Name())) + ".git"
+					if err := migrateRepo(src, repoStaging, migrateOptions{
+						SkipLFS:   migrateSkipLFS,
+						SkipFsck:  migrateSkipFsck,
+						KeepHooks: migrateKeepHooks,
+					}); err != nil {
+						report.warn("failed to migrate repo %s: %s", dir.Name(), err)
+						continue
 					}
 
-					if _, err := sb.CreateRepository(dir.Name(), backend.RepositoryOptions{}); err != nil {
-						fmt.Fprintf(os.Stderr, "failed to create repository: %s\n", err)
+					if _, err := mtx.CreateRepository(dir.Name(), backend.RepositoryOptions{
+						StorageURL: cfg.Storage.URL,
+					}); err != nil {
+						report.warn("failed to create repository %s: %s", dir.Name(), err)
+						continue
 					}
+
+					reposCreated = append(reposCreated, dir.Name())
 				}
 			}
 
 			// Set repos metadata & collabs
 			log.Info("Setting repos metadata & collabs...")
 			for _, repo := range ocfg.Repos {
-				if err := sb.SetProjectName(repo.Repo, repo.Name); err != nil {
-					log.Errorf("failed to set repo name to %s: %s", repo.Repo, err)
+				if err := mtx.SetProjectName(repo.Repo, repo.Name); err != nil {
+					report.warn("failed to set repo name to %s: %s", repo.Repo, err)
 				}
 
-				if err := sb.SetDescription(repo.Repo, repo.Note); err != nil {
-					log.Errorf("failed to set repo description to %s: %s", repo.Repo, err)
+				if err := mtx.SetDescription(repo.Repo, repo.Note); err != nil {
+					report.warn("failed to set repo description to %s: %s", repo.Repo, err)
 				}
 
-				if err := sb.SetPrivate(repo.Repo, repo.Private); err != nil {
-					log.Errorf("failed to set repo private to %s: %s", repo.Repo, err)
+				if err := mtx.SetPrivate(repo.Repo, repo.Private); err != nil {
+					report.warn("failed to set repo private to %s: %s", repo.Repo, err)
 				}
 
 				for _, collab := range repo.Collabs {
-					if err := sb.AddCollaborator(repo.Repo, collab); err != nil {
-						log.Errorf("failed to add repo collab to %s: %s", repo.Repo, err)
+					if err := mtx.AddCollaborator(repo.Repo, collab); err != nil {
+						report.warn("failed to add repo collab to %s: %s", repo.Repo, err)
 					}
 				}
 			}
 
 			// Create users & collabs
 			log.Info("Creating users & collabs...")
+			var usersCreated []string
 			for _, user := range ocfg.Users {
 				keys := make(map[string]ssh.PublicKey)
 				for _, key := range user.PublicKeys {
@@ -198,27 +228,193 @@ var (
 				username := strings.ToLower(user.Name)
 				username = strings.ReplaceAll(username, " ", "-")
 				log.Infof("Creating user %q", username)
-				if _, err := sb.CreateUser(username, backend.UserOptions{
+				if _, err := mtx.CreateUser(username, backend.UserOptions{
 					Admin:      user.Admin,
 					PublicKeys: pubkeys,
 				}); err != nil {
-					log.Errorf("failed to create user: %s", err)
+					report.warn("failed to create user %s: %s", username, err)
+				} else {
+					usersCreated = append(usersCreated, username)
 				}
 
 				for _, repo := range user.CollabRepos {
-					if err := sb.AddCollaborator(repo, username); err != nil {
-						log.Errorf("failed to add user collab to %s: %s\n", repo, err)
+					if err := mtx.AddCollaborator(repo, username); err != nil {
+						report.warn("failed to add user collab to %s: %s", repo, err)
+					}
+				}
+
+				for _, key := range user.PGPKeys {
+					if err := mtx.AddPGPKey(username, key); err != nil {
+						report.warn("failed to add pgp key for %s: %s", username, err)
+					}
+				}
+
+				for _, key := range user.SSHSigningKeys {
+					if err := mtx.AddSSHSigningKey(username, key); err != nil {
+						report.warn("failed to add ssh signing key for %s: %s", username, err)
 					}
 				}
 			}
 
+			// Finalize repo storage: only now do migrated repo files land in
+			// their real location, mirroring the sqlite transaction commit.
+			log.Info("Finalizing repo storage...")
+			for _, name := range reposCreated {
+				src := filepath.Join(staging, "repos", utils.SanitizeRepo(name)) + ".git"
+				dst := utils.SanitizeRepo(name) + ".git"
+				if err := copyDirToStorage(store, src, dst); err != nil {
+					return rollback(fmt.Errorf("failed to finalize repo %s: %w", name, err))
+				}
+				finalizedRepos = append(finalizedRepos, name)
+			}
+			report.ReposCreated = reposCreated
+			report.UsersCreated = usersCreated
+
 			log.Info("Writing config...")
-			defer log.Info("Done!")
-			return config.WriteConfig(filepath.Join(cfg.DataPath, "config.yaml"), cfg)
+			if err := config.WriteConfig(filepath.Join(cfg.DataPath, "config.yaml"), cfg); err != nil {
+				return rollback(fmt.Errorf("failed to write config: %w", err))
+			}
+
+			if err := mtx.Commit(); err != nil {
+				return rollback(fmt.Errorf("failed to commit migration: %w", err))
+			}
+
+			if err := os.RemoveAll(staging); err != nil {
+				log.Errorf("failed to remove staging directory: %s", err)
+			}
+
+			if migrateReportFmt == "json" {
+				if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+					log.Errorf("failed to write migration report: %s", err)
+				}
+			}
+
+			log.Info("Done!")
+			return nil
 		},
 	}
 )
 
+func init() {
+	migrateConfig.Flags().StringVar(&migrateStorageURL, "storage", "", "storage backend URL to migrate repos into, e.g. s3://bucket/prefix (defaults to the on-disk repos directory). Only affects where this migration dump lands -- the running server does not yet serve git operations from s3/gs")
+	migrateConfig.Flags().BoolVar(&migrateSkipLFS, "skip-lfs", false, "don't fetch LFS objects when migrating repos")
+	migrateConfig.Flags().BoolVar(&migrateSkipFsck, "skip-fsck", false, "don't run git fsck --strict on migrated repos")
+	migrateConfig.Flags().BoolVar(&migrateKeepHooks, "keep-hooks", false, "keep all hooks from the source repo instead of only post-receive")
+	migrateConfig.Flags().BoolVar(&migrateDryRun, "dry-run", false, "print every action without touching disk or the database")
+	migrateConfig.Flags().StringVar(&migrateReportFmt, "report", "", "migration report format to print to stdout, e.g. \"json\"")
+}
+
+// migrationReport summarizes a migration run, suitable for CI consumption
+// via --report=json.
+type migrationReport struct {
+	ReposCreated []string `json:"repos_created"`
+	UsersCreated []string `json:"users_created"`
+	Warnings     []string `json:"warnings"`
+}
+
+func (r *migrationReport) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Errorf("%s", msg)
+	r.Warnings = append(r.Warnings, msg)
+}
+
+// readLegacyConfig reads and unmarshals the legacy config.yaml or
+// config.json file out of the "config" repo under reposPath.
+func readLegacyConfig(reposPath string) (Config, error) {
+	var ocfg Config
+
+	r, err := git.Open(filepath.Join(reposPath, "config"))
+	if err != nil {
+		return ocfg, fmt.Errorf("failed to open config repo: %w", err)
+	}
+
+	head, err := r.HEAD()
+	if err != nil {
+		return ocfg, fmt.Errorf("failed to get head: %w", err)
+	}
+
+	tree, err := r.TreePath(head, "")
+	if err != nil {
+		return ocfg, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	isJson := false // nolint: revive
+	te, err := tree.TreeEntry("config.yaml")
+	if err != nil {
+		te, err = tree.TreeEntry("config.json")
+		if err != nil {
+			return ocfg, fmt.Errorf("failed to get config file: %w", err)
+		}
+		isJson = true
+	}
+
+	cc, err := te.Contents()
+	if err != nil {
+		return ocfg, fmt.Errorf("failed to get config contents: %w", err)
+	}
+
+	if isJson {
+		if err := json.Unmarshal(cc, &ocfg); err != nil {
+			return ocfg, fmt.Errorf("failed to unmarshal config: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(cc, &ocfg); err != nil {
+			return ocfg, fmt.Errorf("failed to unmarshal config: %w", err)
+		}
+	}
+
+	return ocfg, nil
+}
+
+// runDryMigration prints every action migrate-config would take without
+// touching disk or the database.
+func runDryMigration(cfg *config.Config, keyPath, reposPath string, report *migrationReport) error {
+	if keyPath != "" {
+		log.Infof("[dry-run] would copy ssh host key from %s", keyPath)
+	}
+
+	ocfg, err := readLegacyConfig(reposPath)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("[dry-run] would set server name to %q", ocfg.Name)
+	log.Infof("[dry-run] would set anon access to %q", ocfg.AnonAccess)
+
+	if reposPath != "" {
+		dirs, err := os.ReadDir(reposPath)
+		if err != nil {
+			return fmt.Errorf("failed to read repos directory: %w", err)
+		}
+
+		for _, dir := range dirs {
+			if !dir.IsDir() || !isGitDir(filepath.Join(reposPath, dir.Name())) {
+				continue
+			}
+			log.Infof("[dry-run] would migrate repo %s", dir.Name())
+			report.ReposCreated = append(report.ReposCreated, dir.Name())
+		}
+	}
+
+	for _, repo := range ocfg.Repos {
+		log.Infof("[dry-run] would set metadata and %d collaborator(s) on %s", len(repo.Collabs), repo.Repo)
+	}
+
+	for _, user := range ocfg.Users {
+		username := strings.ToLower(strings.ReplaceAll(user.Name, " ", "-"))
+		log.Infof("[dry-run] would create user %q with %d collab repo(s)", username, len(user.CollabRepos))
+		report.UsersCreated = append(report.UsersCreated, username)
+	}
+
+	if migrateReportFmt == "json" {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			log.Errorf("failed to write migration report: %s", err)
+		}
+	}
+
+	return nil
+}
+
 // Returns true if path is a directory containing an `objects` directory and a
 // `HEAD` file.
 func isGitDir(path string) bool {
@@ -267,37 +463,188 @@ func copyFile(src, dst string) error {
 	return os.Chmod(dst, srcinfo.Mode())
 }
 
-// copyDir copies a whole directory recursively.
-func copyDir(src string, dst string) error {
-	var err error
-	var fds []os.DirEntry
-	var srcinfo os.FileInfo
+// migrateOptions controls how migrateRepo brings a single repo across.
+type migrateOptions struct {
+	// SkipLFS skips fetching LFS objects even if the source repo uses LFS.
+	SkipLFS bool
+	// SkipFsck skips running `git fsck --strict` on the destination.
+	SkipFsck bool
+	// KeepHooks keeps every hook from the source repo. By default only
+	// `post-receive` is carried over, since other hooks may contain
+	// attacker-controlled scripts from an imported repo.
+	KeepHooks bool
+}
 
-	if srcinfo, err = os.Stat(src); err != nil {
-		return err
+// migrateRepo mirrors the git repo at src into dst, preserving LFS objects,
+// hooks, and info/, and verifying the result with `git fsck --strict`.
+// objects/info/alternates is not carried over (see migrateRepoMetadata).
+// This replaces a plain recursive file copy, which silently drops LFS
+// objects, breaks symlinks, and never verifies the result.
+func migrateRepo(src, dst string, opts migrateOptions) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	cloneArgs := []string{"clone", "--mirror"}
+	if isWorkingRepo(src) {
+		cloneArgs = append(cloneArgs, "--no-local")
+	} else {
+		cloneArgs = append(cloneArgs, "--local")
+	}
+	cloneArgs = append(cloneArgs, src, dst)
+
+	if err := runGit("", cloneArgs...); err != nil {
+		return fmt.Errorf("failed to clone repo: %w", err)
+	}
+
+	if !opts.SkipLFS && usesLFS(src) {
+		if err := runGit(dst, "lfs", "fetch", "--all"); err != nil {
+			return fmt.Errorf("failed to fetch lfs objects: %w", err)
+		}
+	}
+
+	if !opts.SkipFsck {
+		if err := runGit(dst, "fsck", "--strict"); err != nil {
+			return fmt.Errorf("fsck failed on migrated repo: %w", err)
+		}
+	}
+
+	if err := migrateRepoMetadata(src, dst, opts); err != nil {
+		return fmt.Errorf("failed to migrate repo metadata: %w", err)
+	}
+
+	return nil
+}
+
+// isWorkingRepo reports whether path looks like a working copy (i.e. it has
+// a `.git` subdirectory) rather than a bare repository.
+func isWorkingRepo(path string) bool {
+	stat, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil && stat.IsDir()
+}
+
+// usesLFS reports whether the repo at path has LFS objects or an `lfs`
+// filter configured.
+func usesLFS(path string) bool {
+	gitDir := path
+	if isWorkingRepo(path) {
+		gitDir = filepath.Join(path, ".git")
+	}
+
+	if stat, err := os.Stat(filepath.Join(gitDir, "lfs")); err == nil && stat.IsDir() {
+		return true
+	}
+
+	cc, err := os.ReadFile(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return false
 	}
+	return strings.Contains(string(cc), "[filter \"lfs\"]")
+}
 
-	if err = os.MkdirAll(dst, srcinfo.Mode()); err != nil {
+// migrateRepoMetadata copies hooks/ and info/ from src to dst. Hooks other
+// than post-receive are dropped unless opts.KeepHooks is set, since they
+// may contain attacker-controlled scripts from an imported repo.
+//
+// objects/info/alternates is deliberately not carried over: migrateRepo
+// already cloned dst with `git clone --mirror`, which resolves every
+// object reachable through src's alternates into dst's own object store,
+// so copying the alternates file would leave dst pointing at a src path
+// it no longer needs and that may not exist post-migration.
+func migrateRepoMetadata(src, dst string, opts migrateOptions) error {
+	if err := copyDirFiles(filepath.Join(src, "info"), filepath.Join(dst, "info"), nil); err != nil {
 		return err
 	}
 
-	if fds, err = os.ReadDir(src); err != nil {
+	hookFilter := func(name string) bool { return opts.KeepHooks || name == "post-receive" }
+	return copyDirFiles(filepath.Join(src, "hooks"), filepath.Join(dst, "hooks"), hookFilter)
+}
+
+// copyDirFiles copies every regular file directly under src into dst,
+// skipping subdirectories. If keep is non-nil, only files for which it
+// returns true are copied. A missing src directory is not an error.
+func copyDirFiles(src, dst string, keep func(name string) bool) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
 		return err
 	}
-	for _, fd := range fds {
-		srcfp := filepath.Join(src, fd.Name())
-		dstfp := filepath.Join(dst, fd.Name())
 
-		if fd.IsDir() {
-			if err = copyDir(srcfp, dstfp); err != nil {
-				fmt.Println(err)
-			}
-		} else {
-			if err = copyFile(srcfp, dstfp); err != nil {
-				fmt.Println(err)
-			}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if keep != nil && !keep(entry.Name()) {
+			continue
+		}
+		if err := os.MkdirAll(dst, 0700); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runGit runs git with the given args. If dir is non-empty, it is used as
+// the working directory, so git discovers the repository there; no
+// `--git-dir` is passed.
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// copyDirToStorage streams every file under src into the storage backend
+// under the dst key prefix, preserving the relative directory structure.
+func copyDirToStorage(store storage.Backend, src, dst string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
 		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+
+		return store.Put(filepath.ToSlash(filepath.Join(dst, rel)), f)
+	})
+}
+
+// deleteFromStorage removes every object under the prefix key from store.
+// It's used to compensate a finalized repo out of the storage backend when
+// a later step of the migration fails, since copyDirToStorage writes
+// directly into live storage rather than behind a rename-into-place.
+func deleteFromStorage(store storage.Backend, prefix string) error {
+	infos, err := store.List(prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list %s for cleanup: %w", prefix, err)
 	}
+
+	for _, info := range infos {
+		if err := store.Delete(info.Key); err != nil {
+			return fmt.Errorf("failed to delete %s during cleanup: %w", info.Key, err)
+		}
+	}
+
 	return nil
 }
 
@@ -314,10 +661,12 @@ type Config struct {
 
 // User contains user-level configuration for a repository.
 type User struct {
-	Name        string   `yaml:"name" json:"name"`
-	Admin       bool     `yaml:"admin" json:"admin"`
-	PublicKeys  []string `yaml:"public-keys" json:"public-keys"`
-	CollabRepos []string `yaml:"collab-repos" json:"collab-repos"`
+	Name           string   `yaml:"name" json:"name"`
+	Admin          bool     `yaml:"admin" json:"admin"`
+	PublicKeys     []string `yaml:"public-keys" json:"public-keys"`
+	CollabRepos    []string `yaml:"collab-repos" json:"collab-repos"`
+	PGPKeys        []string `yaml:"pgp-keys" json:"pgp-keys"`
+	SSHSigningKeys []string `yaml:"ssh-signing-keys" json:"ssh-signing-keys"`
 }
 
 // RepoConfig is a repository configuration.
@@ -328,4 +677,4 @@ type RepoConfig struct {
 	Private bool     `yaml:"private" json:"private"`
 	Readme  string   `yaml:"readme" json:"readme"`
 	Collabs []string `yaml:"collabs" json:"collabs"`
-}
\ No newline at end of file
+}
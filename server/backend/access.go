@@ -0,0 +1,49 @@
+package backend
+
+// AccessLevel describes a level of access to a repository or the server as
+// a whole.
+type AccessLevel int
+
+const (
+	// NoAccess means the user has no access to the repository.
+	NoAccess AccessLevel = iota
+	// ReadOnlyAccess means the user can only read the repository.
+	ReadOnlyAccess
+	// ReadWriteAccess means the user can read and write to the repository.
+	ReadWriteAccess
+	// AdminAccess means the user has full administrative access.
+	AdminAccess
+)
+
+// String implements fmt.Stringer.
+func (a AccessLevel) String() string {
+	switch a {
+	case NoAccess:
+		return "no-access"
+	case ReadOnlyAccess:
+		return "read-only"
+	case ReadWriteAccess:
+		return "read-write"
+	case AdminAccess:
+		return "admin-access"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAccessLevel parses s into an AccessLevel. It returns -1 if s is not
+// a recognized access level.
+func ParseAccessLevel(s string) AccessLevel {
+	switch s {
+	case "no-access":
+		return NoAccess
+	case "read-only":
+		return ReadOnlyAccess
+	case "read-write":
+		return ReadWriteAccess
+	case "admin-access":
+		return AdminAccess
+	default:
+		return -1
+	}
+}
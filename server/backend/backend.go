@@ -0,0 +1,110 @@
+// Package backend defines the interfaces soft-serve's server uses to read
+// and write repository and user state, independent of the underlying
+// storage engine (see server/backend/sqlite for the sqlite implementation).
+package backend
+
+import (
+	"golang.org/x/crypto/ssh"
+
+	"github.com/charmbracelet/soft-serve/server/backend/storage"
+)
+
+// RepositoryOptions are the options used to create a new repository.
+type RepositoryOptions struct {
+	// Private marks the repository as private.
+	Private bool
+	// StorageURL is the blob-storage backend URL the repository's git
+	// objects live under, e.g. "s3://bucket/prefix". Empty means the
+	// server's default storage backend.
+	StorageURL string
+}
+
+// UserOptions are the options used to create a new user.
+type UserOptions struct {
+	// Admin marks the user as a server administrator.
+	Admin bool
+	// PublicKeys are the user's initial authentication public keys.
+	PublicKeys []ssh.PublicKey
+}
+
+// Repository is a git repository tracked by the backend.
+type Repository interface {
+	// Name is the repository's unique name.
+	Name() string
+	// ProjectName is the repository's human-readable project name.
+	ProjectName() string
+	// Description is the repository's description.
+	Description() string
+	// IsPrivate reports whether the repository is private.
+	IsPrivate() bool
+	// StorageURL is the blob-storage backend URL this repository's git
+	// objects are stored under.
+	StorageURL() string
+}
+
+// User is a soft-serve user account.
+type User interface {
+	// Username is the user's unique username.
+	Username() string
+	// IsAdmin reports whether the user is a server administrator.
+	IsAdmin() bool
+	// PublicKeys are the user's registered authentication public keys.
+	PublicKeys() []ssh.PublicKey
+	// PGPKeys are the user's registered PGP public keys, ASCII armored,
+	// used to verify signed commits.
+	PGPKeys() []string
+	// SSHSigningKeys are the user's registered SSH signing keys, in
+	// authorized_keys format, used to verify signed commits.
+	SSHSigningKeys() []string
+}
+
+// Backend is the interface soft-serve's server uses to read and write
+// repository and user state.
+type Backend interface {
+	// Name is the server's configured name.
+	Name() string
+	// AllowKeyless reports whether keyboard-interactive (keyless) SSH
+	// access is allowed.
+	AllowKeyless() bool
+	// SetAllowKeyless sets whether keyboard-interactive SSH access is
+	// allowed.
+	SetAllowKeyless(allow bool) error
+	// AnonAccess is the access level granted to anonymous users.
+	AnonAccess() AccessLevel
+	// SetAnonAccess sets the access level granted to anonymous users.
+	SetAnonAccess(level AccessLevel) error
+
+	// CreateRepository creates a new repository.
+	CreateRepository(name string, opts RepositoryOptions) (Repository, error)
+	// Repositories lists every repository known to the backend.
+	Repositories() ([]Repository, error)
+	// SetProjectName sets a repository's human-readable project name.
+	SetProjectName(repo, name string) error
+	// SetDescription sets a repository's description.
+	SetDescription(repo, desc string) error
+	// SetPrivate sets whether a repository is private.
+	SetPrivate(repo string, private bool) error
+	// AddCollaborator grants username access to repo.
+	AddCollaborator(repo, username string) error
+	// Collaborators lists the usernames with access to repo.
+	Collaborators(repo string) ([]string, error)
+	// RecordMirror records that repo was imported from the given origin
+	// URL, in the repo_mirrors table.
+	RecordMirror(repo, originURL string) error
+	// RepositoryStorage returns the blob-storage backend configured for
+	// repo, keyed by the StorageURL recorded at CreateRepository time.
+	// Nothing in this tree calls it: there is no git transport
+	// (upload-pack/receive-pack) layer, and migrate-config opens its target
+	// storage with storage.New directly, so pointing a repository at s3/gs
+	// does not affect live reads/writes against the repository.
+	RepositoryStorage(repo string) (storage.Backend, error)
+
+	// CreateUser creates a new user.
+	CreateUser(username string, opts UserOptions) (User, error)
+	// Users lists every user known to the backend.
+	Users() ([]User, error)
+	// AddPGPKey registers a PGP public key for username.
+	AddPGPKey(username, armoredKey string) error
+	// AddSSHSigningKey registers an SSH signing key for username.
+	AddSSHSigningKey(username, authorizedKey string) error
+}
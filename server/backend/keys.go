@@ -0,0 +1,23 @@
+package backend
+
+import (
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ParseAuthorizedKey parses an authorized_keys-format public key line,
+// returning the key and its comment.
+func ParseAuthorizedKey(key string) (ssh.PublicKey, string, error) {
+	pk, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return nil, "", err
+	}
+	return pk, comment, nil
+}
+
+// MarshalAuthorizedKey marshals pk into a single authorized_keys-format
+// line, without a trailing newline.
+func MarshalAuthorizedKey(pk ssh.PublicKey) string {
+	return strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pk)), "\n")
+}
@@ -0,0 +1,250 @@
+// Package signing verifies that pushed commits were authored by signers
+// registered against the pushing user's account. Account identity is a set
+// of signifiers (PGP keys, SSH signing keys) that can prove authorship,
+// decoupled from the SSH transport key used to authenticate the push
+// itself.
+package signing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/openpgp" // nolint: staticcheck
+	"golang.org/x/crypto/ssh"
+)
+
+// Policy controls how a repo treats unsigned or unverifiable commits.
+type Policy string
+
+const (
+	// PolicyOff does not verify commit signatures at all.
+	PolicyOff Policy = "off"
+	// PolicyVerifyOnly verifies signatures when present but does not
+	// reject unsigned or unverified commits, letting the UI flag them.
+	PolicyVerifyOnly Policy = "verify_only"
+	// PolicyRequireSigned rejects any pushed commit that isn't signed by
+	// one of the pushing user's registered keys.
+	PolicyRequireSigned Policy = "require_signed"
+)
+
+// ParsePolicy parses a per-repo signing policy string. It returns
+// PolicyOff if s is empty or unrecognized.
+func ParsePolicy(s string) Policy {
+	switch Policy(s) {
+	case PolicyRequireSigned, PolicyVerifyOnly, PolicyOff:
+		return Policy(s)
+	default:
+		return PolicyOff
+	}
+}
+
+// ErrUnsigned is returned when a commit has no signature.
+var ErrUnsigned = errors.New("signing: commit is not signed")
+
+// ErrUnverified is returned when a commit's signature doesn't match any of
+// the user's registered keys.
+var ErrUnverified = errors.New("signing: commit signature could not be verified")
+
+// Signer owns a set of keys that can prove authorship of a commit.
+type Signer interface {
+	// PGPKeys returns the user's registered PGP public keys, ASCII armored.
+	PGPKeys() []string
+	// SSHSigningKeys returns the user's registered SSH signing keys, in
+	// authorized_keys format.
+	SSHSigningKeys() []string
+}
+
+// Commit is the subset of a git commit needed to verify its signature.
+type Commit struct {
+	// Payload is the commit object with its gpgsig/SSH signature trailer
+	// stripped out, as produced by `git cat-file commit` minus the
+	// signature header.
+	Payload []byte
+	// Signature is the gpgsig (or SSH signature) trailer value.
+	Signature string
+}
+
+// Verify checks commit against signer's registered keys per policy.
+//
+// verified reports whether the commit's signature was confirmed against
+// one of signer's registered keys; it is false under PolicyOff (nothing is
+// checked) and for unsigned or unverifiable commits. err is non-nil only
+// when policy rejects the commit outright (PolicyRequireSigned with a
+// missing or bad signature); under PolicyVerifyOnly err is always nil so
+// callers can surface an unverified commit in the UI without blocking the
+// push.
+func Verify(policy Policy, signer Signer, commit Commit) (verified bool, err error) {
+	if policy == PolicyOff {
+		return false, nil
+	}
+
+	if commit.Signature == "" {
+		if policy == PolicyRequireSigned {
+			return false, ErrUnsigned
+		}
+		return false, nil
+	}
+
+	if err := verifySignature(signer, commit); err != nil {
+		if policy == PolicyRequireSigned {
+			return false, err
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// verifySignature checks commit.Signature against every key signer owns.
+func verifySignature(signer Signer, commit Commit) error {
+	if strings.Contains(commit.Signature, "BEGIN SSH SIGNATURE") {
+		return verifySSHSignature(signer, commit)
+	}
+	return verifyPGPSignature(signer, commit)
+}
+
+func verifyPGPSignature(signer Signer, commit Commit) error {
+	for _, armored := range signer.PGPKeys() {
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+		if err != nil {
+			continue
+		}
+
+		sig := strings.NewReader(commit.Signature)
+		if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(commit.Payload), sig); err == nil {
+			return nil
+		}
+	}
+	return ErrUnverified
+}
+
+func verifySSHSignature(signer Signer, commit Commit) error {
+	wrapper, err := parseSSHSignature(commit.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnverified, err)
+	}
+
+	if wrapper.Namespace != "git" {
+		return fmt.Errorf("%w: unexpected signature namespace %q", ErrUnverified, wrapper.Namespace)
+	}
+
+	hashed, err := hashSSHSigPayload(wrapper.HashAlgorithm, commit.Payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnverified, err)
+	}
+
+	signedData := ssh.Marshal(sshsigSignedData{
+		Namespace:     wrapper.Namespace,
+		Reserved:      wrapper.Reserved,
+		HashAlgorithm: wrapper.HashAlgorithm,
+		Hash:          string(hashed),
+	})
+	signedBlob := append([]byte(sshsigMagic), signedData...)
+
+	var sig ssh.Signature
+	if err := ssh.Unmarshal([]byte(wrapper.Signature), &sig); err != nil {
+		return fmt.Errorf("%w: %s", ErrUnverified, err)
+	}
+
+	embeddedKey, err := ssh.ParsePublicKey([]byte(wrapper.PublicKey))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnverified, err)
+	}
+
+	for _, authorizedKey := range signer.SSHSigningKeys() {
+		pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+		if err != nil {
+			continue
+		}
+		if !bytes.Equal(pk.Marshal(), embeddedKey.Marshal()) {
+			// Not the key this signature claims to be from.
+			continue
+		}
+		if err := pk.Verify(signedBlob, &sig); err == nil {
+			return nil
+		}
+	}
+	return ErrUnverified
+}
+
+// hashSSHSigPayload hashes payload with the named algorithm, as used in the
+// signed blob reconstructed by verifySSHSignature.
+func hashSSHSigPayload(algorithm string, payload []byte) ([]byte, error) {
+	switch algorithm {
+	case "sha256":
+		sum := sha256.Sum256(payload)
+		return sum[:], nil
+	case "sha512":
+		sum := sha512.Sum512(payload)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+}
+
+// sshsigMagic is the fixed 6-byte preamble of the OpenSSH PROTOCOL.sshsig
+// armored signature envelope.
+const sshsigMagic = "SSHSIG"
+
+// sshsigVersion is the only SSHSIG wire format version in use.
+const sshsigVersion = 1
+
+// sshsigWrapper is the sequence of wire-encoded fields that follow the
+// magic preamble and version in an SSHSIG envelope.
+type sshsigWrapper struct {
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// sshsigSignedData is what actually gets signed: the magic preamble
+// followed by these fields, with the message payload replaced by its hash.
+type sshsigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// parseSSHSignature decodes the ASCII-armored OpenSSH PROTOCOL.sshsig
+// envelope produced by `git commit -S` with an SSH signing key (the
+// `-----BEGIN/END SSH SIGNATURE-----` trailer), returning its wire-encoded
+// fields. The envelope wraps a public key, namespace, and hash algorithm
+// around the actual ssh.Signature wire blob, so it has to be unwrapped
+// before ssh.Unmarshal can parse that inner signature.
+func parseSSHSignature(raw string) (*sshsigWrapper, error) {
+	armored := strings.TrimSpace(raw)
+	armored = strings.TrimPrefix(armored, "-----BEGIN SSH SIGNATURE-----")
+	armored = strings.TrimSuffix(armored, "-----END SSH SIGNATURE-----")
+	armored = strings.Join(strings.Fields(armored), "")
+
+	blob, err := base64.StdEncoding.DecodeString(armored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode SSH signature envelope: %w", err)
+	}
+
+	if len(blob) < len(sshsigMagic)+4 || string(blob[:len(sshsigMagic)]) != sshsigMagic {
+		return nil, errors.New("missing SSHSIG magic preamble")
+	}
+	blob = blob[len(sshsigMagic):]
+
+	if version := binary.BigEndian.Uint32(blob[:4]); version != sshsigVersion {
+		return nil, fmt.Errorf("unsupported SSHSIG version %d", version)
+	}
+	blob = blob[4:]
+
+	var wrapper sshsigWrapper
+	if err := ssh.Unmarshal(blob, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse SSHSIG envelope: %w", err)
+	}
+
+	return &wrapper, nil
+}
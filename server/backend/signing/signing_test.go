@@ -0,0 +1,178 @@
+package signing_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/charmbracelet/soft-serve/server/backend/signing"
+)
+
+// sshsigSignedData and sshsigWrapper mirror the wire format of OpenSSH's
+// PROTOCOL.sshsig, reimplemented independently here (rather than reusing
+// signing's unexported types) so this test actually exercises the real
+// envelope format instead of round-tripping through the same code it's
+// meant to catch bugs in.
+type sshsigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+type sshsigWrapper struct {
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+type fakeSigner struct {
+	sshSigningKeys []string
+}
+
+func (f fakeSigner) PGPKeys() []string        { return nil }
+func (f fakeSigner) SSHSigningKeys() []string { return f.sshSigningKeys }
+
+// armorSSHSignature signs payload the way `git commit -S` does with an SSH
+// signing key, producing the ASCII-armored envelope soft-serve's signing
+// package is expected to parse and verify.
+func armorSSHSignature(t *testing.T, key ssh.Signer, payload []byte) string {
+	t.Helper()
+
+	sum := sha512.Sum512(payload)
+	signedData := ssh.Marshal(sshsigSignedData{
+		Namespace:     "git",
+		HashAlgorithm: "sha512",
+		Hash:          string(sum[:]),
+	})
+	signedBlob := append([]byte("SSHSIG"), signedData...)
+
+	sig, err := key.Sign(rand.Reader, signedBlob)
+	if err != nil {
+		t.Fatalf("failed to sign: %s", err)
+	}
+
+	wrapper := ssh.Marshal(sshsigWrapper{
+		PublicKey:     string(key.PublicKey().Marshal()),
+		Namespace:     "git",
+		HashAlgorithm: "sha512",
+		Signature:     string(ssh.Marshal(*sig)),
+	})
+
+	var envelope bytes.Buffer
+	envelope.WriteString("SSHSIG")
+	var version [4]byte
+	binary.BigEndian.PutUint32(version[:], 1)
+	envelope.Write(version[:])
+	envelope.Write(wrapper)
+
+	encoded := base64.StdEncoding.EncodeToString(envelope.Bytes())
+
+	var b strings.Builder
+	b.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 0 {
+		n := 70
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		b.WriteString(encoded[:n])
+		b.WriteByte('\n')
+		encoded = encoded[n:]
+	}
+	b.WriteString("-----END SSH SIGNATURE-----")
+	return b.String()
+}
+
+func TestVerifySSHSignature(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromSigner(priv)
+	if err != nil {
+		t.Fatalf("failed to create ssh signer: %s", err)
+	}
+
+	payload := []byte("tree deadbeef\nauthor test <test@example.com>\n\ncommit message\n")
+	armored := armorSSHSignature(t, signer, payload)
+
+	authorizedKey := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	s := fakeSigner{sshSigningKeys: []string{authorizedKey}}
+
+	verified, err := signing.Verify(signing.PolicyRequireSigned, s, signing.Commit{
+		Payload:   payload,
+		Signature: armored,
+	})
+	if err != nil {
+		t.Fatalf("Verify returned error: %s", err)
+	}
+	if !verified {
+		t.Fatal("expected commit to verify against its signer's registered key")
+	}
+}
+
+func TestVerifySSHSignatureTamperedPayloadRejected(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromSigner(priv)
+	if err != nil {
+		t.Fatalf("failed to create ssh signer: %s", err)
+	}
+
+	payload := []byte("tree deadbeef\nauthor test <test@example.com>\n\ncommit message\n")
+	armored := armorSSHSignature(t, signer, payload)
+
+	authorizedKey := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	s := fakeSigner{sshSigningKeys: []string{authorizedKey}}
+
+	tampered := append(append([]byte(nil), payload...), '!')
+	verified, err := signing.Verify(signing.PolicyRequireSigned, s, signing.Commit{
+		Payload:   tampered,
+		Signature: armored,
+	})
+	if err == nil {
+		t.Fatal("expected a tampered payload to fail verification under PolicyRequireSigned")
+	}
+	if verified {
+		t.Fatal("tampered payload must not verify")
+	}
+}
+
+func TestVerifySSHSignatureUnknownKeyVerifyOnly(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromSigner(priv)
+	if err != nil {
+		t.Fatalf("failed to create ssh signer: %s", err)
+	}
+
+	payload := []byte("tree deadbeef\nauthor test <test@example.com>\n\ncommit message\n")
+	armored := armorSSHSignature(t, signer, payload)
+
+	// Signer has no registered keys, so the signature can't be matched.
+	s := fakeSigner{}
+
+	verified, err := signing.Verify(signing.PolicyVerifyOnly, s, signing.Commit{
+		Payload:   payload,
+		Signature: armored,
+	})
+	if err != nil {
+		t.Fatalf("PolicyVerifyOnly must never reject, got error: %s", err)
+	}
+	if verified {
+		t.Fatal("expected verified=false for a signature that doesn't match any registered key")
+	}
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/server/backend"
+)
+
+// MigrationTx is a single sqlite transaction covering an entire
+// migrate-config run. Its mutating methods mirror SqliteBackend's, so
+// callers can swap sb for mtx without otherwise changing call sites;
+// RecordMirror is the one SqliteBackend mutator it has no counterpart for.
+// Nothing is durably written until Commit is called; Rollback discards
+// every change made through it.
+type MigrationTx struct {
+	sb *SqliteBackend
+	tx *sql.Tx
+}
+
+// BeginMigration starts a new migration transaction.
+func (sb *SqliteBackend) BeginMigration(ctx context.Context) (*MigrationTx, error) {
+	tx, err := sb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &MigrationTx{sb: sb, tx: tx}, nil
+}
+
+// Commit durably applies every change made through mtx.
+func (mtx *MigrationTx) Commit() error {
+	return mtx.tx.Commit()
+}
+
+// Rollback discards every change made through mtx.
+func (mtx *MigrationTx) Rollback() error {
+	return mtx.tx.Rollback()
+}
+
+// CreateRepository mirrors SqliteBackend.CreateRepository.
+func (mtx *MigrationTx) CreateRepository(name string, opts backend.RepositoryOptions) (backend.Repository, error) {
+	return createRepository(mtx.tx, name, opts)
+}
+
+// SetProjectName mirrors SqliteBackend.SetProjectName.
+func (mtx *MigrationTx) SetProjectName(repo, name string) error {
+	_, err := mtx.tx.Exec(`UPDATE repos SET project_name = ? WHERE name = ?`, name, repo)
+	return err
+}
+
+// SetDescription mirrors SqliteBackend.SetDescription.
+func (mtx *MigrationTx) SetDescription(repo, desc string) error {
+	_, err := mtx.tx.Exec(`UPDATE repos SET description = ? WHERE name = ?`, desc, repo)
+	return err
+}
+
+// SetPrivate mirrors SqliteBackend.SetPrivate.
+func (mtx *MigrationTx) SetPrivate(repo string, private bool) error {
+	_, err := mtx.tx.Exec(`UPDATE repos SET private = ? WHERE name = ?`, private, repo)
+	return err
+}
+
+// AddCollaborator mirrors SqliteBackend.AddCollaborator.
+func (mtx *MigrationTx) AddCollaborator(repo, username string) error {
+	return addCollaborator(mtx.tx, repo, username)
+}
+
+// SetAllowKeyless mirrors SqliteBackend.SetAllowKeyless.
+func (mtx *MigrationTx) SetAllowKeyless(allow bool) error {
+	v := "false"
+	if allow {
+		v = "true"
+	}
+	_, err := mtx.tx.Exec(`INSERT INTO settings (key, value) VALUES ('allow_keyless', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, v)
+	return err
+}
+
+// SetAnonAccess mirrors SqliteBackend.SetAnonAccess.
+func (mtx *MigrationTx) SetAnonAccess(level backend.AccessLevel) error {
+	_, err := mtx.tx.Exec(`INSERT INTO settings (key, value) VALUES ('anon_access', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, level.String())
+	return err
+}
+
+// CreateUser mirrors SqliteBackend.CreateUser.
+func (mtx *MigrationTx) CreateUser(username string, opts backend.UserOptions) (backend.User, error) {
+	return createUser(mtx.tx, username, opts)
+}
+
+// AddPGPKey mirrors SqliteBackend.AddPGPKey.
+func (mtx *MigrationTx) AddPGPKey(username, armoredKey string) error {
+	return addPGPKey(mtx.tx, username, armoredKey)
+}
+
+// AddSSHSigningKey mirrors SqliteBackend.AddSSHSigningKey.
+func (mtx *MigrationTx) AddSSHSigningKey(username, authorizedKey string) error {
+	return addSSHSigningKey(mtx.tx, username, authorizedKey)
+}
@@ -0,0 +1,407 @@
+// Package sqlite is the sqlite-backed implementation of server/backend.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"golang.org/x/crypto/ssh"
+
+	_ "modernc.org/sqlite" // sqlite driver, registered as "sqlite"
+
+	"github.com/charmbracelet/soft-serve/server/backend"
+	"github.com/charmbracelet/soft-serve/server/backend/storage"
+	"github.com/charmbracelet/soft-serve/server/config"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS settings (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS repos (
+	name         TEXT PRIMARY KEY,
+	project_name TEXT NOT NULL DEFAULT '',
+	description  TEXT NOT NULL DEFAULT '',
+	private      INTEGER NOT NULL DEFAULT 0,
+	storage_url  TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS repo_collabs (
+	repo     TEXT NOT NULL,
+	username TEXT NOT NULL,
+	UNIQUE(repo, username)
+);
+CREATE TABLE IF NOT EXISTS repo_mirrors (
+	repo       TEXT PRIMARY KEY,
+	origin_url TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS users (
+	username TEXT PRIMARY KEY,
+	admin    INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS user_keys (
+	username   TEXT NOT NULL,
+	public_key TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS user_pgp_keys (
+	username TEXT NOT NULL,
+	pgp_key  TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS user_signing_keys (
+	username      TEXT NOT NULL,
+	authorized_key TEXT NOT NULL
+);
+`
+
+// SqliteBackend is the sqlite-backed implementation of backend.Backend.
+type SqliteBackend struct {
+	db      *sql.DB
+	cfg     *config.Config
+	store   storage.Backend
+	storeMu map[string]storage.Backend
+}
+
+var _ backend.Backend = (*SqliteBackend)(nil)
+
+// NewSqliteBackend opens (creating if necessary) the sqlite database at
+// cfg.DataPath/soft-serve.db and returns a Backend backed by it.
+func NewSqliteBackend(_ context.Context, cfg *config.Config) (*SqliteBackend, error) {
+	dsn := filepath.Join(cfg.DataPath, "soft-serve.db")
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	defaultStoreURL := cfg.Storage.URL
+	if defaultStoreURL == "" {
+		defaultStoreURL = filepath.Join(cfg.DataPath, "repos")
+	}
+	store, err := storage.New(defaultStoreURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create default storage backend: %w", err)
+	}
+
+	return &SqliteBackend{
+		db:      db,
+		cfg:     cfg,
+		store:   store,
+		storeMu: make(map[string]storage.Backend),
+	}, nil
+}
+
+func (sb *SqliteBackend) setting(key, def string) string {
+	var value string
+	err := sb.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (sb *SqliteBackend) setSetting(key, value string) error {
+	_, err := sb.db.Exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+// Name implements backend.Backend.
+func (sb *SqliteBackend) Name() string {
+	return sb.setting("name", "")
+}
+
+// AllowKeyless implements backend.Backend.
+func (sb *SqliteBackend) AllowKeyless() bool {
+	return sb.setting("allow_keyless", "true") == "true"
+}
+
+// SetAllowKeyless implements backend.Backend.
+func (sb *SqliteBackend) SetAllowKeyless(allow bool) error {
+	v := "false"
+	if allow {
+		v = "true"
+	}
+	return sb.setSetting("allow_keyless", v)
+}
+
+// AnonAccess implements backend.Backend.
+func (sb *SqliteBackend) AnonAccess() backend.AccessLevel {
+	return backend.ParseAccessLevel(sb.setting("anon_access", "read-only"))
+}
+
+// SetAnonAccess implements backend.Backend.
+func (sb *SqliteBackend) SetAnonAccess(level backend.AccessLevel) error {
+	return sb.setSetting("anon_access", level.String())
+}
+
+// RepositoryStorage implements backend.Backend. Each repo may point at its
+// own storage URL (set at creation time); repos that don't get the
+// server's default storage backend.
+func (sb *SqliteBackend) RepositoryStorage(repo string) (storage.Backend, error) {
+	var storageURL string
+	err := sb.db.QueryRow(`SELECT storage_url FROM repos WHERE name = ?`, repo).Scan(&storageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up repo %s: %w", repo, err)
+	}
+
+	if storageURL == "" {
+		return sb.store, nil
+	}
+
+	if s, ok := sb.storeMu[storageURL]; ok {
+		return s, nil
+	}
+
+	s, err := storage.New(storageURL)
+	if err != nil {
+		return nil, err
+	}
+	sb.storeMu[storageURL] = s
+	return s, nil
+}
+
+// CreateRepository implements backend.Backend.
+func (sb *SqliteBackend) CreateRepository(name string, opts backend.RepositoryOptions) (backend.Repository, error) {
+	return createRepository(sb.db, name, opts)
+}
+
+func createRepository(q querier, name string, opts backend.RepositoryOptions) (backend.Repository, error) {
+	_, err := q.Exec(`INSERT INTO repos (name, private, storage_url) VALUES (?, ?, ?)`,
+		name, opts.Private, opts.StorageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository %s: %w", name, err)
+	}
+	return &repository{name: name, private: opts.Private, storageURL: opts.StorageURL}, nil
+}
+
+// Repositories implements backend.Backend.
+func (sb *SqliteBackend) Repositories() ([]backend.Repository, error) {
+	rows, err := sb.db.Query(`SELECT name, project_name, description, private, storage_url FROM repos`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // nolint: errcheck
+
+	var repos []backend.Repository
+	for rows.Next() {
+		var r repository
+		if err := rows.Scan(&r.name, &r.projectName, &r.description, &r.private, &r.storageURL); err != nil {
+			return nil, err
+		}
+		repos = append(repos, &r)
+	}
+	return repos, rows.Err()
+}
+
+// SetProjectName implements backend.Backend.
+func (sb *SqliteBackend) SetProjectName(repo, name string) error {
+	_, err := sb.db.Exec(`UPDATE repos SET project_name = ? WHERE name = ?`, name, repo)
+	return err
+}
+
+// SetDescription implements backend.Backend.
+func (sb *SqliteBackend) SetDescription(repo, desc string) error {
+	_, err := sb.db.Exec(`UPDATE repos SET description = ? WHERE name = ?`, desc, repo)
+	return err
+}
+
+// SetPrivate implements backend.Backend.
+func (sb *SqliteBackend) SetPrivate(repo string, private bool) error {
+	_, err := sb.db.Exec(`UPDATE repos SET private = ? WHERE name = ?`, private, repo)
+	return err
+}
+
+// AddCollaborator implements backend.Backend.
+func (sb *SqliteBackend) AddCollaborator(repo, username string) error {
+	return addCollaborator(sb.db, repo, username)
+}
+
+func addCollaborator(q querier, repo, username string) error {
+	_, err := q.Exec(`INSERT OR IGNORE INTO repo_collabs (repo, username) VALUES (?, ?)`, repo, username)
+	return err
+}
+
+// Collaborators implements backend.Backend.
+func (sb *SqliteBackend) Collaborators(repo string) ([]string, error) {
+	rows, err := sb.db.Query(`SELECT username FROM repo_collabs WHERE repo = ?`, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // nolint: errcheck
+
+	var collabs []string
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+		collabs = append(collabs, username)
+	}
+	return collabs, rows.Err()
+}
+
+// RecordMirror implements backend.Backend.
+func (sb *SqliteBackend) RecordMirror(repo, originURL string) error {
+	_, err := sb.db.Exec(`INSERT INTO repo_mirrors (repo, origin_url) VALUES (?, ?)
+		ON CONFLICT(repo) DO UPDATE SET origin_url = excluded.origin_url`, repo, originURL)
+	return err
+}
+
+// CreateUser implements backend.Backend.
+func (sb *SqliteBackend) CreateUser(username string, opts backend.UserOptions) (backend.User, error) {
+	return createUser(sb.db, username, opts)
+}
+
+func createUser(q querier, username string, opts backend.UserOptions) (backend.User, error) {
+	_, err := q.Exec(`INSERT INTO users (username, admin) VALUES (?, ?)`, username, opts.Admin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user %s: %w", username, err)
+	}
+
+	for _, pk := range opts.PublicKeys {
+		if _, err := q.Exec(`INSERT INTO user_keys (username, public_key) VALUES (?, ?)`,
+			username, backend.MarshalAuthorizedKey(pk)); err != nil {
+			return nil, fmt.Errorf("failed to add public key for %s: %w", username, err)
+		}
+	}
+
+	return &user{username: username, admin: opts.Admin, publicKeys: opts.PublicKeys}, nil
+}
+
+// Users implements backend.Backend.
+func (sb *SqliteBackend) Users() ([]backend.User, error) {
+	rows, err := sb.db.Query(`SELECT username, admin FROM users`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // nolint: errcheck
+
+	var users []backend.User
+	for rows.Next() {
+		var u user
+		if err := rows.Scan(&u.username, &u.admin); err != nil {
+			return nil, err
+		}
+
+		if err := sb.loadUserKeys(&u); err != nil {
+			return nil, err
+		}
+
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+func (sb *SqliteBackend) loadUserKeys(u *user) error {
+	rows, err := sb.db.Query(`SELECT public_key FROM user_keys WHERE username = ?`, u.username)
+	if err != nil {
+		return err
+	}
+	defer rows.Close() // nolint: errcheck
+
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return err
+		}
+		pk, _, err := backend.ParseAuthorizedKey(raw)
+		if err != nil {
+			continue
+		}
+		u.publicKeys = append(u.publicKeys, pk)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	pgpRows, err := sb.db.Query(`SELECT pgp_key FROM user_pgp_keys WHERE username = ?`, u.username)
+	if err != nil {
+		return err
+	}
+	defer pgpRows.Close() // nolint: errcheck
+	for pgpRows.Next() {
+		var key string
+		if err := pgpRows.Scan(&key); err != nil {
+			return err
+		}
+		u.pgpKeys = append(u.pgpKeys, key)
+	}
+	if err := pgpRows.Err(); err != nil {
+		return err
+	}
+
+	signRows, err := sb.db.Query(`SELECT authorized_key FROM user_signing_keys WHERE username = ?`, u.username)
+	if err != nil {
+		return err
+	}
+	defer signRows.Close() // nolint: errcheck
+	for signRows.Next() {
+		var key string
+		if err := signRows.Scan(&key); err != nil {
+			return err
+		}
+		u.sshSigningKeys = append(u.sshSigningKeys, key)
+	}
+	return signRows.Err()
+}
+
+// AddPGPKey implements backend.Backend.
+func (sb *SqliteBackend) AddPGPKey(username, armoredKey string) error {
+	return addPGPKey(sb.db, username, armoredKey)
+}
+
+func addPGPKey(q querier, username, armoredKey string) error {
+	_, err := q.Exec(`INSERT INTO user_pgp_keys (username, pgp_key) VALUES (?, ?)`, username, armoredKey)
+	return err
+}
+
+// AddSSHSigningKey implements backend.Backend.
+func (sb *SqliteBackend) AddSSHSigningKey(username, authorizedKey string) error {
+	return addSSHSigningKey(sb.db, username, authorizedKey)
+}
+
+func addSSHSigningKey(q querier, username, authorizedKey string) error {
+	_, err := q.Exec(`INSERT INTO user_signing_keys (username, authorized_key) VALUES (?, ?)`, username, authorizedKey)
+	return err
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx, letting the mutating
+// helpers above run either directly or inside a MigrationTx.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// repository implements backend.Repository.
+type repository struct {
+	name        string
+	projectName string
+	description string
+	private     bool
+	storageURL  string
+}
+
+func (r *repository) Name() string        { return r.name }
+func (r *repository) ProjectName() string { return r.projectName }
+func (r *repository) Description() string { return r.description }
+func (r *repository) IsPrivate() bool     { return r.private }
+func (r *repository) StorageURL() string  { return r.storageURL }
+
+// user implements backend.User.
+type user struct {
+	username       string
+	admin          bool
+	publicKeys     []ssh.PublicKey
+	pgpKeys        []string
+	sshSigningKeys []string
+}
+
+func (u *user) Username() string            { return u.username }
+func (u *user) IsAdmin() bool               { return u.admin }
+func (u *user) PublicKeys() []ssh.PublicKey { return u.publicKeys }
+func (u *user) PGPKeys() []string           { return u.pgpKeys }
+func (u *user) SSHSigningKeys() []string    { return u.sshSigningKeys }
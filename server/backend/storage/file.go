@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileBackend is a Backend that stores objects as files under a root
+// directory on the local filesystem. This is the backend used before
+// object-storage support existed, kept as the default driver.
+type FileBackend struct {
+	root string
+}
+
+var _ Backend = (*FileBackend)(nil)
+
+// NewFileBackend returns a Backend rooted at dir.
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{root: dir}
+}
+
+func (f *FileBackend) path(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
+
+// Get implements Backend.
+func (f *FileBackend) Get(key string) (io.ReadCloser, error) {
+	file, err := os.Open(f.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return file, err
+}
+
+// Put implements Backend.
+func (f *FileBackend) Put(key string, r io.Reader) error {
+	p := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+
+	_, err = io.Copy(file, r)
+	return err
+}
+
+// List implements Backend.
+func (f *FileBackend) List(prefix string) ([]Info, error) {
+	root := f.path(prefix)
+	var infos []Info
+	err := filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.root, p)
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, Info{Key: filepath.ToSlash(rel), Size: fi.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// Delete implements Backend.
+func (f *FileBackend) Delete(key string) error {
+	err := os.Remove(f.path(key))
+	if os.IsNotExist(err) {
+		return ErrNotExist
+	}
+	return err
+}
+
+// Stat implements Backend.
+func (f *FileBackend) Stat(key string) (Info, error) {
+	fi, err := os.Stat(f.path(key))
+	if os.IsNotExist(err) {
+		return Info{}, ErrNotExist
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Key: key, Size: fi.Size()}, nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GCSBackend is a Backend backed by a Google Cloud Storage bucket.
+type GCSBackend struct {
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+var _ Backend = (*GCSBackend)(nil)
+
+// NewGCSBackend returns a Backend for the bucket and prefix encoded in u,
+// e.g. gs://bucket/prefix.
+func NewGCSBackend(u *url.URL) (*GCSBackend, error) {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCSBackend{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+func (g *GCSBackend) key(key string) string {
+	if g.prefix == "" {
+		return key
+	}
+	return g.prefix + "/" + key
+}
+
+func (g *GCSBackend) handle(key string) *storage.ObjectHandle {
+	return g.client.Bucket(g.bucket).Object(g.key(key))
+}
+
+// Get implements Backend.
+func (g *GCSBackend) Get(key string) (io.ReadCloser, error) {
+	r, err := g.handle(key).NewReader(context.Background())
+	if err == storage.ErrObjectNotExist {
+		return nil, ErrNotExist
+	}
+	return r, err
+}
+
+// Put implements Backend.
+func (g *GCSBackend) Put(key string, r io.Reader) error {
+	w := g.handle(key).NewWriter(context.Background())
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close() // nolint: errcheck
+		return err
+	}
+	return w.Close()
+}
+
+// List implements Backend.
+func (g *GCSBackend) List(prefix string) ([]Info, error) {
+	var infos []Info
+	it := g.client.Bucket(g.bucket).Objects(context.Background(), &storage.Query{
+		Prefix: g.key(prefix),
+	})
+
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, Info{
+			Key:  strings.TrimPrefix(attrs.Name, g.prefix+"/"),
+			Size: attrs.Size,
+		})
+	}
+
+	return infos, nil
+}
+
+// Delete implements Backend.
+func (g *GCSBackend) Delete(key string) error {
+	err := g.handle(key).Delete(context.Background())
+	if err == storage.ErrObjectNotExist {
+		return ErrNotExist
+	}
+	return err
+}
+
+// Stat implements Backend.
+func (g *GCSBackend) Stat(key string) (Info, error) {
+	attrs, err := g.handle(key).Attrs(context.Background())
+	if err == storage.ErrObjectNotExist {
+		return Info{}, ErrNotExist
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Key: key, Size: attrs.Size}, nil
+}
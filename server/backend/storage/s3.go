@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithy "github.com/aws/smithy-go"
+)
+
+// S3Backend is a Backend backed by an S3 (or S3-compatible) bucket.
+type S3Backend struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+var _ Backend = (*S3Backend)(nil)
+
+// NewS3Backend returns a Backend for the bucket and prefix encoded in u,
+// e.g. s3://bucket/prefix.
+func NewS3Backend(u *url.URL) (*S3Backend, error) {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Backend{
+		client: s3.NewFromConfig(cfg),
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+func (s *S3Backend) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+// Get implements Backend.
+func (s *S3Backend) Get(key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		if isNotExist(err) {
+			return nil, ErrNotExist
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// Put implements Backend.
+func (s *S3Backend) Put(key string, r io.Reader) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+		Body:   r,
+	})
+	return err
+}
+
+// List implements Backend.
+func (s *S3Backend) List(prefix string) ([]Info, error) {
+	var infos []Info
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.key(prefix)),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Contents {
+			infos = append(infos, Info{
+				Key:  strings.TrimPrefix(aws.ToString(obj.Key), s.prefix+"/"),
+				Size: aws.ToInt64(obj.Size),
+			})
+		}
+	}
+
+	return infos, nil
+}
+
+// Delete implements Backend.
+func (s *S3Backend) Delete(key string) error {
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	return err
+}
+
+// Stat implements Backend.
+func (s *S3Backend) Stat(key string) (Info, error) {
+	out, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		if isNotExist(err) {
+			return Info{}, ErrNotExist
+		}
+		return Info{}, err
+	}
+	return Info{Key: key, Size: aws.ToInt64(out.ContentLength)}, nil
+}
+
+// isNotExist reports whether err represents a missing S3 object, as
+// returned by GetObject (NoSuchKey) or HeadObject (NotFound).
+func isNotExist(err error) bool {
+	var nsk *types.NoSuchKey
+	if errors.As(err, &nsk) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return true
+		}
+	}
+	return false
+}
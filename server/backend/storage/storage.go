@@ -0,0 +1,77 @@
+// Package storage provides a pluggable blob-storage abstraction used by
+// backends to persist repository data. The on-disk "repos/" tree used by
+// the sqlite backend is one implementation; object-storage drivers such as
+// s3 and gcs are meant to let soft-serve run statelessly against a shared
+// bucket.
+//
+// Known scope cut: that goal isn't delivered yet. A Backend is only ever
+// read or written by migrate-config's bulk copy today -- there
+// is no git transport (upload-pack/receive-pack) layer in this tree that
+// reads or writes a live repository through it. Pointing --storage at
+// s3/gs changes where the migration dump lands, not where a running
+// server serves git operations from. Wiring that up is follow-on work,
+// not something this package alone can finish.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrNotExist is returned when a key does not exist in the backend.
+var ErrNotExist = errors.New("storage: object does not exist")
+
+// Info describes a single stored object.
+type Info struct {
+	// Key is the object's key relative to the backend's root.
+	Key string
+	// Size is the object size in bytes.
+	Size int64
+}
+
+// Backend is a pluggable blob-storage backend. Keys are opaque, slash
+// separated paths rooted at the backend's configured prefix.
+type Backend interface {
+	// Get returns a reader for the object stored at key.
+	Get(key string) (io.ReadCloser, error)
+	// Put writes r to the object stored at key, overwriting any existing
+	// object.
+	Put(key string, r io.Reader) error
+	// List returns the keys of all objects under prefix.
+	List(prefix string) ([]Info, error)
+	// Delete removes the object stored at key.
+	Delete(key string) error
+	// Stat returns metadata about the object stored at key.
+	Stat(key string) (Info, error)
+}
+
+// New returns a Backend for the given storage URL. Supported schemes are
+// "file" (the default, a plain directory on disk), "s3", and "gs".
+//
+//	file:///data/repos
+//	s3://bucket/prefix
+//	gs://bucket/prefix
+func New(rawURL string) (Backend, error) {
+	if rawURL == "" || !strings.Contains(rawURL, "://") {
+		return NewFileBackend(rawURL), nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid url %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		return NewFileBackend(u.Path), nil
+	case "s3":
+		return NewS3Backend(u)
+	case "gs":
+		return NewGCSBackend(u)
+	default:
+		return nil, fmt.Errorf("storage: unsupported scheme %q", u.Scheme)
+	}
+}
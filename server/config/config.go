@@ -0,0 +1,68 @@
+// Package config holds soft-serve's server configuration.
+package config
+
+import (
+	"os"
+
+	"github.com/charmbracelet/soft-serve/server/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// SSHConfig holds the SSH server's configuration.
+type SSHConfig struct {
+	ListenAddr string `yaml:"listen_addr"`
+	PublicURL  string `yaml:"public_url"`
+	KeyPath    string `yaml:"key_path"`
+}
+
+// StorageConfig holds the blob-storage backend configuration used to
+// persist repository git objects.
+type StorageConfig struct {
+	// URL is the storage backend URL, e.g. "s3://bucket/prefix" or
+	// "gs://bucket/prefix". An empty URL means the on-disk repos
+	// directory under DataPath.
+	URL string `yaml:"url"`
+}
+
+// Config is soft-serve's server configuration.
+type Config struct {
+	Name     string        `yaml:"name"`
+	DataPath string        `yaml:"-"`
+	SSH      SSHConfig     `yaml:"ssh"`
+	Storage  StorageConfig `yaml:"storage"`
+
+	// Backend is the repository/user backend in use. It is not
+	// serialized; it's wired up at runtime via WithBackend.
+	Backend backend.Backend `yaml:"-"`
+}
+
+// DefaultConfig returns a Config populated from the environment, falling
+// back to soft-serve's conventional defaults.
+func DefaultConfig() *Config {
+	dataPath := os.Getenv("SOFT_SERVE_DATA_PATH")
+	if dataPath == "" {
+		dataPath = ".soft-serve"
+	}
+
+	return &Config{
+		DataPath: dataPath,
+		SSH: SSHConfig{
+			ListenAddr: ":23231",
+		},
+	}
+}
+
+// WithBackend sets cfg's backend and returns cfg for chaining.
+func (cfg *Config) WithBackend(b backend.Backend) *Config {
+	cfg.Backend = b
+	return cfg
+}
+
+// WriteConfig writes cfg as YAML to path.
+func WriteConfig(path string, cfg *Config) error {
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}